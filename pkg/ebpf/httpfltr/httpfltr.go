@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
+	"fmt"
 	"io"
 	"net"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	ebpfcommon "github.com/grafana/ebpf-autoinstrument/pkg/ebpf/common"
 	"github.com/grafana/ebpf-autoinstrument/pkg/exec"
@@ -17,6 +19,7 @@ import (
 	"golang.org/x/exp/slog"
 
 	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
 	"github.com/cilium/ebpf/ringbuf"
 	"github.com/grafana/ebpf-autoinstrument/pkg/goexec"
 )
@@ -24,24 +27,124 @@ import (
 //go:generate $BPF2GO -cc $BPF_CLANG -cflags $BPF_CFLAGS -target amd64,arm64 bpf ../../../bpf/http_sock.c -- -I../../../bpf/headers
 //go:generate $BPF2GO -cc $BPF_CLANG -cflags $BPF_CFLAGS -target amd64,arm64 bpf_debug ../../../bpf/http_sock.c -- -I../../../bpf/headers -DBPF_DEBUG
 
-var activePids, _ = lru.New[uint32, string](64)
+// activePids caches resolved ServiceAttrs keyed by (pid namespace, pid), so
+// repeated events from the same process don't re-read /proc and the cgroup
+// hierarchy on every call.
+var activePids, _ = lru.New[ebpfcommon.PidKey, ebpfcommon.ServiceAttrs](64)
+
+// connTuple identifies a single TCP connection by its full source/target
+// address and port, for pairing a request with its response. Two concurrent
+// connections between the same pair of hosts (e.g. an HTTP/1.1 connection
+// pool) only differ by port, so the address alone isn't enough to tell them
+// apart.
+type connTuple struct {
+	source, target       string
+	sourcePort, destPort uint16
+}
+
+// pendingRequests holds the start timestamp of a request until its response
+// arrives on the same connection, so toRequestTrace can compute latency.
+// It's a userspace LRU rather than a BPF hash map keyed by connection: under
+// sustained load beyond its capacity, the oldest in-flight request is
+// evicted and its latency silently lost, so onRequestEvicted at least logs
+// when that happens.
+var pendingRequests, _ = lru.NewWithEvict[connTuple, uint64](1024, onRequestEvicted)
+
+func onRequestEvicted(tuple connTuple, _ uint64) {
+	logger().Warn("evicted in-flight request before its response arrived, latency lost",
+		"source", tuple.source, "sourcePort", tuple.sourcePort,
+		"target", tuple.target, "destPort", tuple.destPort)
+}
+
+// partialBuffers holds HTTP header data captured so far for a connection
+// whose headers didn't fit in a single ringbuf record, keyed by connTuple so
+// the next record on the same connection can be stitched onto it. See
+// reassemble.
+var partialBuffers, _ = lru.New[connTuple, []byte](1024)
+
+// maxPartialBufferSize bounds how much data reassemble accumulates for a
+// single connection before giving up on waiting for the rest, so a
+// connection that never produces a recognizable terminator can't grow
+// partialBuffers without bound.
+const maxPartialBufferSize = 8 * 1024
+
+// reassemble stitches chunk onto any data already buffered for tuple. A
+// chunk that doesn't fill its whole capture buffer (i.e. contains a NUL
+// byte) means the kernel captured its message in full; one that fills it
+// completely may have been cut off mid-header, so it's held in
+// partialBuffers until a later record on the same connection completes it.
+func reassemble(tuple connTuple, chunk []byte) (full []byte, complete bool) {
+	full = chunk
+	if prev, ok := partialBuffers.Get(tuple); ok {
+		full = append(append([]byte{}, prev...), chunk...)
+	}
+
+	if bytes.IndexByte(chunk, 0) >= 0 || len(full) >= maxPartialBufferSize {
+		partialBuffers.Remove(tuple)
+		return full, true
+	}
+
+	partialBuffers.Add(tuple, full)
+	return full, false
+}
+
+// tracerName identifies this Tracer's resources under Cfg.PinPath.
+const tracerName = "httpfltr"
 
 type BPFHTTPInfo bpfHttpInfoT
 type BPFConnInfo bpfConnectionInfoT
 
 type HTTPInfo struct {
 	BPFHTTPInfo
-	Method string
-	URL    string
-	Comm   string
-	Host   string
-	Peer   string
+	Method  string
+	URL     string
+	Version string
+	Comm    string
+	Host    string
+	Peer    string
+	// Attrs carries the service/container attributes resolved for this
+	// event's process in SystemWide mode (see Tracer.serviceAttrs), e.g.
+	// cgroup.path or, with a Kubernetes-aware ServiceNameResolver,
+	// k8s.namespace/k8s.pod.name/k8s.container.name.
+	Attrs map[string]string
+
+	// StatusCode is the response status, only set (and > 0) when this event
+	// captured a response line rather than a request line.
+	StatusCode int
+	// HostHeader, UserAgent, RequestID, TraceParent and ContentLength are
+	// pulled out of the captured buffer's headers. ContentLength is -1 when
+	// the header is absent or unparseable.
+	HostHeader    string
+	UserAgent     string
+	RequestID     string
+	TraceParent   string
+	ContentLength int64
+	// Headers holds any extra header configured via
+	// ebpfcommon.TracerConfig.HeaderAllowlist, keyed lower-case.
+	Headers map[string]string
+
+	// RequestStart and ResponseEnd are kernel ktime nanosecond timestamps.
+	// ResponseEnd is set on every response event; RequestStart and Latency
+	// (ResponseEnd - RequestStart) only when a request on the same connection
+	// was observed before that response.
+	RequestStart uint64
+	ResponseEnd  uint64
+	Latency      time.Duration
+
+	// Partial is true when this event's buffer was held by reassemble to
+	// wait for the rest of its headers on a later record from the same
+	// connection; only BPFHTTPInfo, Host and Peer are populated yet.
+	Partial bool
 }
 
 type Tracer struct {
 	Cfg        *ebpfcommon.TracerConfig
 	bpfObjects bpfObjects
 	closers    []io.Closer
+	// links holds the probes attached through AddLink, keyed by the stable
+	// name they were attached under (e.g. "xdp/eth0/0"), so PinResources can
+	// pin them by that name across a restart instead of by attach order.
+	links map[string]link.Link
 }
 
 func logger() *slog.Logger {
@@ -56,6 +159,125 @@ func (p *Tracer) Load() (*ebpf.CollectionSpec, error) {
 	return loader()
 }
 
+// pinBaseDir returns the directory this Tracer pins its resources under, or
+// "" when Cfg.PinPath is unset and pinning is disabled.
+func (p *Tracer) pinBaseDir() string {
+	if p.Cfg.PinPath == "" {
+		return ""
+	}
+
+	return filepath.Join(p.Cfg.PinPath, "beyla", tracerName)
+}
+
+// LoadOptions returns the ebpf.CollectionOptions to use when turning the
+// CollectionSpec from Load into bpfObjects, pointing maps at pinBaseDir so a
+// previous PinResources call's maps get reopened instead of recreated.
+func (p *Tracer) LoadOptions() *ebpf.CollectionOptions {
+	dir := p.pinBaseDir()
+	if dir == "" {
+		return nil
+	}
+
+	return &ebpf.CollectionOptions{
+		Maps: ebpf.MapOptions{PinPath: dir},
+	}
+}
+
+// pinnedLink wraps a link.Link that has been (or is about to be) pinned to
+// bpffs; Close is a no-op since the link must outlive this process.
+type pinnedLink struct{ link.Link }
+
+func (pinnedLink) Close() error { return nil }
+
+// linkPinPath is the path a link attached under name would be pinned to,
+// given pinning is enabled.
+func (p *Tracer) linkPinPath(name string) string {
+	return filepath.Join(p.pinBaseDir(), "link-"+name)
+}
+
+// loadPinnedLink re-opens a link previously pinned under name, so a restart
+// can pick up an already-attached probe instead of detaching and
+// re-attaching it. Returns ok=false when pinning is disabled or nothing is
+// pinned yet under that name.
+func (p *Tracer) loadPinnedLink(name string) (l link.Link, ok bool) {
+	dir := p.pinBaseDir()
+	if dir == "" {
+		return nil, false
+	}
+
+	l, err := link.LoadPinnedLink(p.linkPinPath(name), nil)
+	if err != nil {
+		return nil, false
+	}
+
+	return l, true
+}
+
+// AddLink tracks l as a closer, like AddCloser, under the stable name it was
+// attached for (e.g. "xdp/eth0/0"), so PinResources and loadPinnedLink can
+// find it again by name rather than by attach order.
+func (p *Tracer) AddLink(name string, l link.Link) {
+	if p.links == nil {
+		p.links = map[string]link.Link{}
+	}
+	p.links[name] = l
+
+	if p.pinBaseDir() != "" {
+		p.AddCloser(pinnedLink{l})
+		return
+	}
+
+	p.AddCloser(l)
+}
+
+// PinResources pins the maps this Tracer owns, and every link tracked via
+// AddLink that isn't already pinned (e.g. because it was just reopened by
+// loadPinnedLink), under pinBaseDir so a subsequent restart can reattach to
+// the same resources instead of losing in-flight state. A no-op when
+// Cfg.PinPath is unset.
+func (p *Tracer) PinResources() error {
+	dir := p.pinBaseDir()
+	if dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating pin directory %s: %w", dir, err)
+	}
+
+	maps := map[string]*ebpf.Map{
+		"events":    p.bpfObjects.Events,
+		"dead_pids": p.bpfObjects.DeadPids,
+	}
+
+	for name, m := range maps {
+		if m.IsPinned() {
+			continue
+		}
+
+		if err := m.Pin(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("pinning map %s: %w", name, err)
+		}
+	}
+
+	for name, l := range p.links {
+		if l.IsPinned() {
+			continue // reopened from a previous generation's pin
+		}
+
+		path := p.linkPinPath(name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("creating pin directory for link %s: %w", name, err)
+		}
+
+		if err := l.Pin(path); err != nil {
+			return fmt.Errorf("pinning link %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
 func (p *Tracer) Constants(finfo *exec.FileInfo, _ *goexec.Offsets) map[string]any {
 	if p.Cfg.SystemWide {
 		return nil
@@ -85,6 +307,10 @@ func (p *Tracer) GoProbes() map[string]ebpfcommon.FunctionPrograms {
 	return nil
 }
 
+// KProbes returns the kprobe-based fallback programs for socket lifecycle and
+// data tracking. On kernels that support fentry/fexit (see FentryPrograms),
+// the functions that have a fentry/fexit counterpart are removed from this
+// map so they aren't attached twice.
 func (p *Tracer) KProbes() map[string]ebpfcommon.FunctionPrograms {
 	kprobes := map[string]ebpfcommon.FunctionPrograms{
 		// Both sys accept probes use the same kretprobe.
@@ -130,13 +356,167 @@ func (p *Tracer) KProbes() map[string]ebpfcommon.FunctionPrograms {
 		}
 	}
 
+	if p.Cfg.PreferFentry() {
+		delete(kprobes, "sys_accept4")
+		delete(kprobes, "sock_alloc")
+		delete(kprobes, "tcp_rcv_established")
+		delete(kprobes, "sys_connect")
+		delete(kprobes, "tcp_connect")
+	}
+
+	if p.Cfg.PreferTracepoints() {
+		// Covered by Tracepoints() instead: the internal accept/connect/exit
+		// helpers these kprobes tap into aren't part of the kernel's stable
+		// ABI, unlike the tracepoints.
+		delete(kprobes, "sys_accept")
+		delete(kprobes, "sys_accept4")
+		delete(kprobes, "sys_connect")
+		delete(kprobes, "sys_exit")
+		delete(kprobes, "sys_exit_group")
+	}
+
 	return kprobes
 }
 
+// Tracepoints returns the tracepoint-based replacements for the
+// sys_accept/sys_accept4/sys_connect/sys_exit kprobes above, keyed by
+// "category/name" as expected by link.Tracepoint. Tracepoints have a stable
+// ABI across kernel versions, unlike the internal kernel functions the
+// equivalent kprobes above tap into (see the comment on KProbes). Returns nil
+// when the kernel can't resolve tracepoint arguments through BTF, in which
+// case KProbes keeps the kprobe fallback active.
+func (p *Tracer) Tracepoints() map[string]ebpfcommon.FunctionPrograms {
+	if !p.Cfg.PreferTracepoints() {
+		return nil
+	}
+
+	tracepoints := map[string]ebpfcommon.FunctionPrograms{
+		"syscalls/sys_enter_accept4": {
+			Required: true,
+			Start:    p.bpfObjects.TracepointSysEnterAccept4,
+		},
+		"syscalls/sys_exit_accept4": {
+			Required: true,
+			Start:    p.bpfObjects.TracepointSysExitAccept4,
+		},
+		"syscalls/sys_enter_connect": {
+			Required: true,
+			Start:    p.bpfObjects.TracepointSysEnterConnect,
+		},
+		"syscalls/sys_exit_connect": {
+			Required: true,
+			Start:    p.bpfObjects.TracepointSysExitConnect,
+		},
+	}
+
+	// Same as the sys_exit/sys_exit_group kprobes above: only needed in
+	// SystemWide mode, to resolve the names of processes that have since
+	// exited.
+	if p.Cfg.SystemWide {
+		tracepoints["sched/sched_process_exit"] = ebpfcommon.FunctionPrograms{
+			Required: true,
+			Start:    p.bpfObjects.TracepointSchedProcessExit,
+		}
+	}
+
+	return tracepoints
+}
+
+// FentryPrograms returns the fentry/fexit counterparts of the kprobes removed
+// from KProbes when the kernel supports BPF_PROG_TYPE_TRACING. Returns nil
+// when the kernel doesn't support it. sys_accept4/sys_connect are omitted
+// when PreferTracepoints is also true: tracepoints take precedence for those
+// two, since Tracepoints() already covers them, and attaching both would
+// instrument every accept/connect twice.
+func (p *Tracer) FentryPrograms() map[string]ebpfcommon.FunctionPrograms {
+	if !p.Cfg.PreferFentry() {
+		return nil
+	}
+
+	fentry := map[string]ebpfcommon.FunctionPrograms{
+		"sock_alloc": {
+			Required: true,
+			End:      p.bpfObjects.FexitSockAlloc,
+		},
+		"tcp_rcv_established": {
+			Required: true,
+			Start:    p.bpfObjects.FentryTcpRcvEstablished,
+		},
+		"tcp_connect": {
+			Required: true,
+			Start:    p.bpfObjects.FentryTcpConnect,
+		},
+	}
+
+	if !p.Cfg.PreferTracepoints() {
+		fentry["sys_accept4"] = ebpfcommon.FunctionPrograms{
+			Required: true,
+			End:      p.bpfObjects.FexitSysAccept4,
+		}
+		fentry["sys_connect"] = ebpfcommon.FunctionPrograms{
+			Required: true,
+			End:      p.bpfObjects.FexitSysConnect,
+		}
+	}
+
+	return fentry
+}
+
 func (p *Tracer) SocketFilters() []*ebpf.Program {
 	return []*ebpf.Program{p.bpfObjects.SocketHttpFilter}
 }
 
+// XDPPrograms returns the XDP programs that capture HTTP request lines at NIC
+// ingress, for traffic that bypasses the normal socket delivery path (e.g.
+// heavy connection churn or userspace network stacks) where tcp_rcv_established
+// is too expensive to tap into.
+func (p *Tracer) XDPPrograms() []*ebpf.Program {
+	return []*ebpf.Program{p.bpfObjects.XdpHttpFilter}
+}
+
+// AttachXDP binds the XDPPrograms to every interface configured in
+// Cfg.XDPInterfaces, using Cfg.XDPAttachMode to pick between SKB, Native and
+// Offload mode. Each link is tracked via AddLink under a name stable across
+// restarts ("xdp/<interface>/<program index>"); when pinning is enabled, a
+// link already pinned under that name from a previous generation is reopened
+// instead of attached fresh.
+func (p *Tracer) AttachXDP() error {
+	if len(p.Cfg.XDPInterfaces) == 0 {
+		return nil
+	}
+
+	flags := p.Cfg.XDPAttachMode.LinkFlags()
+
+	for _, name := range p.Cfg.XDPInterfaces {
+		iface, err := net.InterfaceByName(name)
+		if err != nil {
+			return fmt.Errorf("looking up XDP interface %s: %w", name, err)
+		}
+
+		for i, prog := range p.XDPPrograms() {
+			linkName := fmt.Sprintf("xdp/%s/%d", name, i)
+
+			if l, ok := p.loadPinnedLink(linkName); ok {
+				p.AddLink(linkName, l)
+				continue
+			}
+
+			l, err := link.AttachXDP(link.XDPOptions{
+				Program:   prog,
+				Interface: iface.Index,
+				Flags:     flags,
+			})
+			if err != nil {
+				return fmt.Errorf("attaching XDP program to %s: %w", name, err)
+			}
+
+			p.AddLink(linkName, l)
+		}
+	}
+
+	return nil
+}
+
 func (p *Tracer) Run(ctx context.Context, eventsChan chan<- []any) {
 	ebpfcommon.ForwardRingbuf(
 		p.Cfg, logger(), p.bpfObjects.Events, p.toRequestTrace,
@@ -158,37 +538,180 @@ func (p *Tracer) toRequestTrace(record *ringbuf.Record) (any, error) {
 	source, target := event.hostInfo()
 	result.Host = target
 	result.Peer = source
-	result.URL = event.url()
-	result.Method = event.method()
+
+	tuple := event.connTuple()
+	buf, complete := reassemble(tuple, event.Buf[:])
+	if !complete {
+		result.Partial = true
+		return result, nil
+	}
+
+	lines := httpLines(buf)
+	result.URL = httpURL(lines)
+	result.Method = httpMethod(lines)
+	result.Version = httpVersion(lines)
 	if p.Cfg.SystemWide {
-		result.Comm = p.serviceName(event.Pid)
+		attrs := p.serviceAttrs(event.PidNs, event.Pid)
+		result.Comm = attrs.Comm
+		result.Attrs = attrs.Attrs
+	}
+
+	headers := parseHeaders(lines, p.Cfg.HeaderAllowlist)
+	result.HostHeader = headers.host
+	result.UserAgent = headers.userAgent
+	result.RequestID = headers.requestID
+	result.TraceParent = headers.traceParent
+	result.ContentLength = headers.contentLength
+	result.Headers = headers.extra
+
+	if code, ok := httpStatusCode(lines); ok {
+		result.StatusCode = code
+		result.ResponseEnd = event.Timestamp
+
+		if start, ok := pendingRequests.Get(tuple); ok {
+			result.RequestStart = start
+			result.Latency = time.Duration(result.ResponseEnd-result.RequestStart) * time.Nanosecond
+			pendingRequests.Remove(tuple)
+		}
+	} else {
+		pendingRequests.Add(tuple, event.Timestamp)
 	}
 
 	return result, nil
 }
 
-func (event *BPFHTTPInfo) url() string {
-	buf := string(event.Buf[:])
-	space := strings.Index(buf, " ")
-	if space < 0 {
+// httpLines splits a captured (and possibly reassembled, see reassemble)
+// buffer into its CRLF-delimited lines, stopping at the first NUL byte.
+func httpLines(buf []byte) []string {
+	return strings.Split(cstr(buf), "\r\n")
+}
+
+// httpMethod returns the first field of the first line: the request method
+// (e.g. "GET") for a request line, or the version token (e.g. "HTTP/1.1") for
+// a status line. Returns "" when the first line is empty or lines is empty.
+func httpMethod(lines []string) string {
+	fields := firstLineFields(lines)
+	if len(fields) == 0 {
 		return ""
 	}
-	nextSpace := strings.Index(buf[space+1:], " ")
-	if nextSpace < 0 {
+
+	return fields[0]
+}
+
+// httpURL returns the second field of the first line: the request target for
+// a request line, or the status code (e.g. "200") for a status line. Returns
+// "" when the first line has fewer than two fields.
+func httpURL(lines []string) string {
+	fields := firstLineFields(lines)
+	if len(fields) < 2 {
 		return ""
 	}
 
-	return buf[space+1 : nextSpace+space+1]
+	return fields[1]
 }
 
-func (event *BPFHTTPInfo) method() string {
-	buf := string(event.Buf[:])
-	space := strings.Index(buf, " ")
-	if space < 0 {
-		return ""
+// httpVersion returns the HTTP version token (e.g. "1.1") from the request or
+// status line, or "" if none is present.
+func httpVersion(lines []string) string {
+	for _, field := range firstLineFields(lines) {
+		if v, ok := strings.CutPrefix(field, "HTTP/"); ok {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// httpStatusCode reports the response status code when lines starts with a
+// status line ("HTTP/1.1 200 OK"), rather than a request line.
+func httpStatusCode(lines []string) (int, bool) {
+	fields := firstLineFields(lines)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "HTTP/") {
+		return 0, false
+	}
+
+	code, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return code, true
+}
+
+func firstLineFields(lines []string) []string {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	return strings.Fields(lines[0])
+}
+
+// parsedHeaders is the result of parseHeaders: the handful of headers a
+// Tracer always pulls out, plus any extra header matched against the
+// configured allowlist.
+type parsedHeaders struct {
+	host          string
+	userAgent     string
+	requestID     string
+	traceParent   string
+	contentLength int64
+	extra         map[string]string
+}
+
+// parseHeaders walks the header lines following the request/status line,
+// extracting Host, User-Agent, X-Request-Id, traceparent and Content-Length
+// unconditionally, plus any header listed in allowlist (case-insensitive)
+// into extra.
+func parseHeaders(lines []string, allowlist []string) parsedHeaders {
+	result := parsedHeaders{contentLength: -1}
+
+	allowed := make(map[string]struct{}, len(allowlist))
+	for _, h := range allowlist {
+		allowed[strings.ToLower(h)] = struct{}{}
+	}
+
+	if len(lines) < 2 {
+		return result
+	}
+
+	for _, line := range lines[1:] {
+		if line == "" {
+			break // blank line separates headers from body
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+
+		switch name {
+		case "host":
+			result.host = value
+		case "user-agent":
+			result.userAgent = value
+		case "x-request-id":
+			result.requestID = value
+		case "traceparent":
+			result.traceParent = value
+		case "content-length":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				result.contentLength = n
+			}
+		}
+
+		if _, ok := allowed[name]; ok {
+			if result.extra == nil {
+				result.extra = map[string]string{}
+			}
+
+			result.extra[name] = value
+		}
 	}
 
-	return buf[:space]
+	return result
 }
 
 func (event *BPFHTTPInfo) hostInfo() (source, target string) {
@@ -200,6 +723,20 @@ func (event *BPFHTTPInfo) hostInfo() (source, target string) {
 	return src.String(), dst.String()
 }
 
+// connTuple identifies the TCP connection this event was captured on,
+// including source and destination ports, so concurrent connections between
+// the same pair of hosts don't collapse onto the same key.
+func (event *BPFHTTPInfo) connTuple() connTuple {
+	source, target := event.hostInfo()
+
+	return connTuple{
+		source:     source,
+		target:     target,
+		sourcePort: event.ConnInfo.S_port,
+		destPort:   event.ConnInfo.D_port,
+	}
+}
+
 func cstr(chars []uint8) string {
 	addrLen := bytes.IndexByte(chars[:], 0)
 	if addrLen < 0 {
@@ -219,28 +756,33 @@ func (p *Tracer) commNameOfDeadPid(pid uint32) string {
 	return cstr(name[:])
 }
 
-func (p *Tracer) commName(pid uint32) string {
-	procPath := filepath.Join("/proc", strconv.FormatUint(uint64(pid), 10), "comm")
-	_, err := os.Stat(procPath)
-	if os.IsNotExist(err) {
-		return p.commNameOfDeadPid(pid)
+// resolver returns the configured ServiceNameResolver, falling back to
+// ebpfcommon.DefaultServiceNameResolver when none is set.
+func (p *Tracer) resolver() ebpfcommon.ServiceNameResolver {
+	if p.Cfg.ServiceNameResolver != nil {
+		return p.Cfg.ServiceNameResolver
 	}
 
-	name, err := os.ReadFile(procPath)
-	if err != nil {
-		p.commNameOfDeadPid(pid)
-	}
-
-	return strings.TrimSpace(string(name))
+	return ebpfcommon.DefaultServiceNameResolver{}
 }
 
-func (p *Tracer) serviceName(pid uint32) string {
-	cached, ok := activePids.Get(pid)
+// serviceAttrs resolves the ServiceAttrs of the process identified by
+// (pidNs, pid), caching the result in activePids. If the process has already
+// exited, its comm is recovered from the BPF-side DeadPids map instead of
+// /proc.
+func (p *Tracer) serviceAttrs(pidNs, pid uint32) ebpfcommon.ServiceAttrs {
+	key := ebpfcommon.PidKey{NsID: pidNs, Pid: pid}
+
+	cached, ok := activePids.Get(key)
 	if ok {
 		return cached
 	}
 
-	name := p.commName(pid)
-	activePids.Add(pid, name)
-	return name
+	attrs := p.resolver().Resolve(key)
+	if attrs.Comm == "" {
+		attrs.Comm = p.commNameOfDeadPid(pid)
+	}
+
+	activePids.Add(key, attrs)
+	return attrs
 }
\ No newline at end of file
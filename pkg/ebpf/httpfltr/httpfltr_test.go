@@ -0,0 +1,156 @@
+package httpfltr
+
+import "testing"
+
+func TestHTTPLines(t *testing.T) {
+	buf := append([]byte("GET /foo HTTP/1.1\r\nHost: example.com\r\n\r\n"), make([]byte, 10)...)
+
+	lines := httpLines(buf)
+
+	want := []string{"GET /foo HTTP/1.1", "Host: example.com", "", ""}
+	if len(lines) != len(want) {
+		t.Fatalf("httpLines(%q) = %v, want %v", buf, lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("httpLines(%q)[%d] = %q, want %q", buf, i, lines[i], want[i])
+		}
+	}
+}
+
+func TestHTTPMethodURLVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		method  string
+		url     string
+		version string
+	}{
+		{"request line", "GET /foo/bar HTTP/1.1", "GET", "/foo/bar", "1.1"},
+		{"status line", "HTTP/1.1 200 OK", "HTTP/1.1", "200", "1.1"},
+		{"empty", "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := []string{tt.line}
+
+			if got := httpMethod(lines); got != tt.method {
+				t.Errorf("httpMethod(%q) = %q, want %q", tt.line, got, tt.method)
+			}
+			if got := httpURL(lines); got != tt.url {
+				t.Errorf("httpURL(%q) = %q, want %q", tt.line, got, tt.url)
+			}
+			if got := httpVersion(lines); got != tt.version {
+				t.Errorf("httpVersion(%q) = %q, want %q", tt.line, got, tt.version)
+			}
+		})
+	}
+}
+
+func TestHTTPStatusCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		code  int
+		ok    bool
+	}{
+		{"status line", []string{"HTTP/1.1 404 Not Found"}, 404, true},
+		{"request line", []string{"GET / HTTP/1.1"}, 0, false},
+		{"unparseable code", []string{"HTTP/1.1 OK"}, 0, false},
+		{"no lines", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, ok := httpStatusCode(tt.lines)
+			if code != tt.code || ok != tt.ok {
+				t.Errorf("httpStatusCode(%v) = (%d, %v), want (%d, %v)", tt.lines, code, ok, tt.code, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	lines := []string{
+		"GET /foo HTTP/1.1",
+		"Host: example.com",
+		"User-Agent: curl/8.0",
+		"X-Request-Id: abc-123",
+		"traceparent: 00-trace-span-01",
+		"Content-Length: 42",
+		"X-Custom: keep-me",
+		"X-Other: drop-me",
+		"",
+	}
+
+	got := parseHeaders(lines, []string{"X-Custom"})
+
+	if got.host != "example.com" {
+		t.Errorf("host = %q, want %q", got.host, "example.com")
+	}
+	if got.userAgent != "curl/8.0" {
+		t.Errorf("userAgent = %q, want %q", got.userAgent, "curl/8.0")
+	}
+	if got.requestID != "abc-123" {
+		t.Errorf("requestID = %q, want %q", got.requestID, "abc-123")
+	}
+	if got.traceParent != "00-trace-span-01" {
+		t.Errorf("traceParent = %q, want %q", got.traceParent, "00-trace-span-01")
+	}
+	if got.contentLength != 42 {
+		t.Errorf("contentLength = %d, want 42", got.contentLength)
+	}
+	if got.extra["x-custom"] != "keep-me" {
+		t.Errorf("extra[x-custom] = %q, want %q", got.extra["x-custom"], "keep-me")
+	}
+	if _, ok := got.extra["x-other"]; ok {
+		t.Errorf("extra[x-other] should not be captured, got %q", got.extra["x-other"])
+	}
+}
+
+func TestParseHeadersDefaultContentLength(t *testing.T) {
+	got := parseHeaders([]string{"GET / HTTP/1.1", ""}, nil)
+
+	if got.contentLength != -1 {
+		t.Errorf("contentLength = %d, want -1 when absent", got.contentLength)
+	}
+}
+
+func TestReassemble(t *testing.T) {
+	tuple := connTuple{source: "10.0.0.1", target: "10.0.0.2", sourcePort: 1234, destPort: 80}
+
+	full := []byte("GET /foo HTTP/1.1\r\n")
+
+	if _, complete := reassemble(tuple, full); complete {
+		t.Fatalf("reassemble() = complete on a chunk with no NUL terminator, want incomplete")
+	}
+
+	tail := append([]byte("Host: example.com\r\n\r\n"), 0)
+	got, complete := reassemble(tuple, tail)
+	if !complete {
+		t.Fatalf("reassemble() = incomplete after a NUL-terminated tail, want complete")
+	}
+
+	lines := httpLines(got)
+	if lines[0] != "GET /foo HTTP/1.1" {
+		t.Errorf("reassembled first line = %q, want request line", lines[0])
+	}
+	if lines[1] != "Host: example.com" {
+		t.Errorf("reassembled second line = %q, want Host header", lines[1])
+	}
+}
+
+func TestReassembleSingleChunk(t *testing.T) {
+	tuple := connTuple{source: "10.0.0.1", target: "10.0.0.2", sourcePort: 1234, destPort: 80}
+
+	buf := append([]byte("GET / HTTP/1.1\r\n\r\n"), 0)
+
+	got, complete := reassemble(tuple, buf)
+	if !complete {
+		t.Fatalf("reassemble() = incomplete for a single NUL-terminated chunk, want complete")
+	}
+	if httpMethod(httpLines(got)) != "GET" {
+		t.Errorf("reassembled method = %q, want GET", httpMethod(httpLines(got)))
+	}
+}
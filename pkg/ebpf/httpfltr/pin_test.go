@@ -0,0 +1,38 @@
+package httpfltr
+
+import (
+	"path/filepath"
+	"testing"
+
+	ebpfcommon "github.com/grafana/ebpf-autoinstrument/pkg/ebpf/common"
+)
+
+func TestPinBaseDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		pinPath string
+		want    string
+	}{
+		{"disabled", "", ""},
+		{"enabled", "/sys/fs/bpf", filepath.Join("/sys/fs/bpf", "beyla", tracerName)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := Tracer{Cfg: &ebpfcommon.TracerConfig{PinPath: tt.pinPath}}
+
+			if got := tracer.pinBaseDir(); got != tt.want {
+				t.Errorf("pinBaseDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkPinPath(t *testing.T) {
+	tracer := Tracer{Cfg: &ebpfcommon.TracerConfig{PinPath: "/sys/fs/bpf"}}
+
+	want := filepath.Join("/sys/fs/bpf", "beyla", tracerName, "link-xdp/eth0/0")
+	if got := tracer.linkPinPath("xdp/eth0/0"); got != want {
+		t.Errorf("linkPinPath() = %q, want %q", got, want)
+	}
+}
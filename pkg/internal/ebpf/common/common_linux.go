@@ -3,6 +3,7 @@ package ebpfcommon
 import (
 	"syscall"
 
+	"github.com/cilium/ebpf/link"
 	"golang.org/x/sys/unix"
 )
 
@@ -10,6 +11,19 @@ func (f *Filter) Close() error {
 	return syscall.SetsockoptInt(f.Fd, unix.SOL_SOCKET, unix.SO_DETACH_BPF, 0)
 }
 
+// LinkFlags translates an XDPAttachMode into the cilium/ebpf link.XDPAttachFlags
+// value that link.AttachXDP expects.
+func (m XDPAttachMode) LinkFlags() link.XDPAttachFlags {
+	switch m {
+	case XDPAttachModeNative:
+		return link.XDPDriverMode
+	case XDPAttachModeOffload:
+		return link.XDPOffloadMode
+	default:
+		return link.XDPGenericMode
+	}
+}
+
 // Copied from https://github.com/golang/go/blob/go1.21.3/src/internal/syscall/unix/kernel_version_linux.go
 func KernelVersion() (major, minor int) {
 	var uname syscall.Utsname
@@ -38,3 +52,25 @@ func KernelVersion() (major, minor int) {
 
 	return values[0], values[1]
 }
+
+// KernelRelease returns the raw `uname -r` string (e.g. "5.15.0-91-generic"),
+// unlike KernelVersion, which only keeps the leading N.N numeric components.
+// Distro kernel config files are named after the full release string, so
+// callers that need to find one (e.g. kernelConfig's /boot/config-$(uname -r)
+// fallback) should use this instead.
+func KernelRelease() (string, error) {
+	var uname syscall.Utsname
+	if err := syscall.Uname(&uname); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 0, len(uname.Release))
+	for _, c := range uname.Release {
+		if c == 0 {
+			break
+		}
+		buf = append(buf, byte(c))
+	}
+
+	return string(buf), nil
+}
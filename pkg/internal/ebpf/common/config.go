@@ -0,0 +1,72 @@
+package ebpfcommon
+
+// TracerConfig holds the common configuration options shared by the
+// different eBPF-based tracers (httpfltr and any future protocol tracers).
+type TracerConfig struct {
+	// BpfDebug makes the tracer load the *_debug BPF object, which is
+	// compiled with extra bpf_printk instrumentation.
+	BpfDebug bool
+
+	// SystemWide instruments all the processes in the host, instead of a
+	// single instrumented executable.
+	SystemWide bool
+
+	// XDPAttachMode selects how the XDP programs returned by a Tracer's
+	// XDPPrograms method are attached to XDPInterfaces.
+	XDPAttachMode XDPAttachMode
+
+	// XDPInterfaces is the list of network interface names (as understood
+	// by net.InterfaceByName) that XDP programs will be attached to. If
+	// empty, no XDP program is attached.
+	XDPInterfaces []string
+
+	// PinPath is the bpffs directory under which a Tracer pins its maps and
+	// links (e.g. /sys/fs/bpf). When empty, pinning is disabled and a
+	// restart always loads fresh resources. When set, each Tracer pins its
+	// own resources under PinPath/beyla/<tracer>/ so a restart can re-open
+	// them instead of losing in-flight state (e.g. active connections
+	// tracked by the accept kretprobe).
+	PinPath string
+
+	// ServiceNameResolver resolves the service identity of a captured
+	// event's pid/pid-namespace, for SystemWide mode. When nil, a Tracer
+	// falls back to DefaultServiceNameResolver.
+	ServiceNameResolver ServiceNameResolver
+
+	// HeaderAllowlist lists extra HTTP header names (case-insensitive) a
+	// Tracer should capture into HTTPInfo.Headers, beyond the handful
+	// (Host, User-Agent, X-Request-Id, traceparent, Content-Length) it
+	// always extracts.
+	HeaderAllowlist []string
+}
+
+// PreferFentry reports whether the running kernel supports fentry/fexit
+// (BPF_PROG_TYPE_TRACING) programs, which attach by function name through
+// BTF instead of the raw kprobe symbols that can get inlined or renamed.
+func (c *TracerConfig) PreferFentry() bool {
+	return Features().HasFentry
+}
+
+// PreferTracepoints reports whether the running kernel can resolve
+// tracepoint arguments through BTF, so a Tracer can use the stable
+// syscalls/sched tracepoints instead of the equivalent kprobes.
+func (c *TracerConfig) PreferTracepoints() bool {
+	return Features().HasTracepointBTF
+}
+
+// XDPAttachMode selects the mechanism the kernel uses to run an XDP program
+// on a given network interface.
+type XDPAttachMode int
+
+const (
+	// XDPAttachModeSKB attaches the program in generic/SKB mode. It works on
+	// any network driver, at the cost of running after the sk_buff has
+	// already been allocated.
+	XDPAttachModeSKB XDPAttachMode = iota
+	// XDPAttachModeNative attaches the program in the driver's native XDP
+	// hook, before the sk_buff is allocated. Requires driver support.
+	XDPAttachModeNative
+	// XDPAttachModeOffload offloads the program to a SmartNIC. Requires
+	// hardware support.
+	XDPAttachModeOffload
+)
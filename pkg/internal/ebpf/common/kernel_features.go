@@ -0,0 +1,120 @@
+package ebpfcommon
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/btf"
+	"github.com/cilium/ebpf/features"
+)
+
+// KernelFeatures describes the BPF-related capabilities of the kernel we are
+// running on, so a Tracer can pick the cheapest/most stable program variant
+// that the kernel actually supports.
+type KernelFeatures struct {
+	// HasBTF is true when the kernel exposes BTF type information, either
+	// built-in (/sys/kernel/btf/vmlinux) or loadable.
+	HasBTF bool
+	// HasFentry is true when BPF_PROG_TYPE_TRACING programs (fentry/fexit)
+	// can be loaded, which requires HasBTF.
+	HasFentry bool
+	// HasRingbuf is true when BPF_MAP_TYPE_RINGBUF is supported.
+	HasRingbuf bool
+	// HasTracepointBTF is true when tracepoint arguments can be read through
+	// their BTF-typed representation (BPF_PROG_TYPE_TRACEPOINT + CO-RE),
+	// rather than the raw tracepoint format.
+	HasTracepointBTF bool
+}
+
+var (
+	kernelFeaturesOnce sync.Once
+	kernelFeatures     KernelFeatures
+)
+
+// Features detects and caches the KernelFeatures of the running kernel. The
+// detection only runs once per process: subsequent calls return the cached
+// result.
+func Features() KernelFeatures {
+	kernelFeaturesOnce.Do(func() {
+		kernelFeatures = detectFeatures()
+	})
+
+	return kernelFeatures
+}
+
+func detectFeatures() KernelFeatures {
+	_, err := btf.LoadKernelSpec()
+	hasBTF := err == nil
+
+	hasFentry := hasBTF && features.HaveProgramType(ebpf.Tracing) == nil
+	hasRingbuf := features.HaveMapType(ebpf.RingBuf) == nil
+
+	cfg, _ := kernelConfig()
+	hasTracepointBTF := hasBTF && cfg["CONFIG_DEBUG_INFO_BTF"] == "y"
+
+	return KernelFeatures{
+		HasBTF:           hasBTF,
+		HasFentry:        hasFentry,
+		HasRingbuf:       hasRingbuf,
+		HasTracepointBTF: hasTracepointBTF,
+	}
+}
+
+// kernelConfig returns the kernel's CONFIG_* build options, read from
+// /proc/config.gz if available, falling back to /boot/config-$(uname -r).
+func kernelConfig() (map[string]string, error) {
+	f, err := os.Open("/proc/config.gz")
+	if err == nil {
+		defer f.Close()
+
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading /proc/config.gz: %w", err)
+		}
+		defer gz.Close()
+
+		return parseKernelConfig(gz)
+	}
+
+	release, err := KernelRelease()
+	if err != nil {
+		return nil, fmt.Errorf("no kernel config available: %w", err)
+	}
+
+	path := "/boot/config-" + release
+
+	bootCfg, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("no kernel config available: %w", err)
+	}
+	defer bootCfg.Close()
+
+	return parseKernelConfig(bootCfg)
+}
+
+func parseKernelConfig(r io.Reader) (map[string]string, error) {
+	cfg := map[string]string{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		cfg[kv[0]] = kv[1]
+	}
+
+	return cfg, scanner.Err()
+}
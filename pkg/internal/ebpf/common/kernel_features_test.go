@@ -0,0 +1,53 @@
+package ebpfcommon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseKernelConfig(t *testing.T) {
+	in := strings.Join([]string{
+		"# auto-generated, do not edit",
+		"",
+		"CONFIG_DEBUG_INFO_BTF=y",
+		"CONFIG_BPF_SYSCALL=y",
+		"# CONFIG_BPF_JIT is not set",
+		"CONFIG_HZ=250",
+		"malformed line without equals",
+	}, "\n")
+
+	cfg, err := parseKernelConfig(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("parseKernelConfig() error = %v", err)
+	}
+
+	want := map[string]string{
+		"CONFIG_DEBUG_INFO_BTF": "y",
+		"CONFIG_BPF_SYSCALL":    "y",
+		"CONFIG_HZ":             "250",
+	}
+
+	if len(cfg) != len(want) {
+		t.Fatalf("parseKernelConfig() = %v, want %v", cfg, want)
+	}
+
+	for k, v := range want {
+		if cfg[k] != v {
+			t.Errorf("parseKernelConfig()[%q] = %q, want %q", k, cfg[k], v)
+		}
+	}
+
+	if _, ok := cfg["CONFIG_BPF_JIT"]; ok {
+		t.Errorf("parseKernelConfig() should skip commented-out options, got CONFIG_BPF_JIT = %q", cfg["CONFIG_BPF_JIT"])
+	}
+}
+
+func TestParseKernelConfigEmpty(t *testing.T) {
+	cfg, err := parseKernelConfig(strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("parseKernelConfig() error = %v", err)
+	}
+	if len(cfg) != 0 {
+		t.Errorf("parseKernelConfig(\"\") = %v, want empty", cfg)
+	}
+}
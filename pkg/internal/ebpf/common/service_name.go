@@ -0,0 +1,116 @@
+package ebpfcommon
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PidKey uniquely identifies a process across PID namespaces by pairing its
+// namespace id with its in-namespace pid. In SystemWide mode, pid alone can
+// conflate unrelated processes in different containers that happen to reuse
+// the same pid.
+type PidKey struct {
+	NsID uint32
+	Pid  uint32
+}
+
+// ServiceAttrs describes the service/process a captured event belongs to.
+// Attrs carries orchestrator metadata (e.g. k8s.namespace, k8s.pod.name,
+// k8s.container.name) when a ServiceNameResolver can supply it; the default
+// resolver only fills in cgroup.path and mnt.ns.
+type ServiceAttrs struct {
+	Comm  string
+	Attrs map[string]string
+}
+
+// ServiceNameResolver resolves a PidKey into the ServiceAttrs identifying the
+// service running there. The DefaultServiceNameResolver only reads /proc and
+// the process's cgroup v2 path; users running under Kubernetes can plug in a
+// kubelet- or CRI-backed implementation that maps the cgroup path to pod and
+// container metadata instead.
+type ServiceNameResolver interface {
+	Resolve(key PidKey) ServiceAttrs
+}
+
+// procRoot is the /proc mountpoint DefaultServiceNameResolver reads from.
+// Overridable in tests.
+var procRoot = "/proc"
+
+// DefaultServiceNameResolver resolves a PidKey using only information
+// available locally under /proc: the process comm, its cgroup v2 path and
+// its mount namespace id.
+type DefaultServiceNameResolver struct{}
+
+func (DefaultServiceNameResolver) Resolve(key PidKey) ServiceAttrs {
+	attrs := ServiceAttrs{
+		Comm:  procComm(key.Pid),
+		Attrs: map[string]string{},
+	}
+
+	if path, ok := cgroupPath(key.Pid); ok {
+		attrs.Attrs["cgroup.path"] = path
+	}
+
+	if mntNs, err := mountNamespaceID(key.Pid); err == nil {
+		attrs.Attrs["mnt.ns"] = strconv.FormatUint(uint64(mntNs), 10)
+	}
+
+	return attrs
+}
+
+func procComm(pid uint32) string {
+	path := filepath.Join(procRoot, strconv.FormatUint(uint64(pid), 10), "comm")
+
+	name, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(name))
+}
+
+// cgroupPath returns the unified (cgroup v2) path for pid, as found in its
+// /proc/PID/cgroup entry with an empty controller list (the "0::" line).
+func cgroupPath(pid uint32) (string, bool) {
+	path := filepath.Join(procRoot, strconv.FormatUint(uint64(pid), 10), "cgroup")
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "0::") {
+			continue
+		}
+
+		return strings.TrimPrefix(line, "0::"), true
+	}
+
+	return "", false
+}
+
+// mountNamespaceID returns the inode number of pid's mount namespace, as
+// found in the /proc/PID/ns/mnt symlink target ("mnt:[<inode>]").
+func mountNamespaceID(pid uint32) (uint32, error) {
+	path := filepath.Join(procRoot, strconv.FormatUint(uint64(pid), 10), "ns", "mnt")
+
+	target, err := os.Readlink(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading mount namespace of pid %d: %w", pid, err)
+	}
+
+	var id uint32
+	if _, err := fmt.Sscanf(target, "mnt:[%d]", &id); err != nil {
+		return 0, fmt.Errorf("parsing mount namespace link %q: %w", target, err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,86 @@
+package ebpfcommon
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func withFakeProc(t *testing.T, pid uint32, comm, cgroup string, mntNsInode int) {
+	t.Helper()
+
+	root := t.TempDir()
+	pidDir := filepath.Join(root, strconv.FormatUint(uint64(pid), 10))
+
+	if err := os.MkdirAll(filepath.Join(pidDir, "ns"), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	if comm != "" {
+		if err := os.WriteFile(filepath.Join(pidDir, "comm"), []byte(comm+"\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile comm: %v", err)
+		}
+	}
+
+	if cgroup != "" {
+		if err := os.WriteFile(filepath.Join(pidDir, "cgroup"), []byte(cgroup), 0o644); err != nil {
+			t.Fatalf("WriteFile cgroup: %v", err)
+		}
+	}
+
+	target := "mnt:[" + strconv.Itoa(mntNsInode) + "]"
+	if err := os.Symlink(target, filepath.Join(pidDir, "ns", "mnt")); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+
+	old := procRoot
+	procRoot = root
+	t.Cleanup(func() { procRoot = old })
+}
+
+func TestCgroupPath(t *testing.T) {
+	withFakeProc(t, 123, "myapp", "0::/system.slice/myapp.service\n", 456)
+
+	path, ok := cgroupPath(123)
+	if !ok {
+		t.Fatalf("cgroupPath() ok = false, want true")
+	}
+	if want := "/system.slice/myapp.service"; path != want {
+		t.Errorf("cgroupPath() = %q, want %q", path, want)
+	}
+}
+
+func TestCgroupPathMissing(t *testing.T) {
+	if _, ok := cgroupPath(999999); ok {
+		t.Errorf("cgroupPath() for nonexistent pid ok = true, want false")
+	}
+}
+
+func TestMountNamespaceID(t *testing.T) {
+	withFakeProc(t, 123, "myapp", "0::/\n", 4026531840)
+
+	id, err := mountNamespaceID(123)
+	if err != nil {
+		t.Fatalf("mountNamespaceID() error = %v", err)
+	}
+	if id != 4026531840 {
+		t.Errorf("mountNamespaceID() = %d, want 4026531840", id)
+	}
+}
+
+func TestDefaultServiceNameResolverResolve(t *testing.T) {
+	withFakeProc(t, 123, "myapp", "0::/system.slice/myapp.service\n", 456)
+
+	attrs := DefaultServiceNameResolver{}.Resolve(PidKey{NsID: 1, Pid: 123})
+
+	if attrs.Comm != "myapp" {
+		t.Errorf("Comm = %q, want %q", attrs.Comm, "myapp")
+	}
+	if want := "/system.slice/myapp.service"; attrs.Attrs["cgroup.path"] != want {
+		t.Errorf("Attrs[cgroup.path] = %q, want %q", attrs.Attrs["cgroup.path"], want)
+	}
+	if attrs.Attrs["mnt.ns"] != "456" {
+		t.Errorf("Attrs[mnt.ns] = %q, want %q", attrs.Attrs["mnt.ns"], "456")
+	}
+}